@@ -0,0 +1,24 @@
+package bus
+
+// BrokerMessage 是 Broker 投递给某个 receiver 的一条原始消息。
+type BrokerMessage struct {
+	// Topic 是消息实际所在的主题；订阅通配符主题时，它是被命中的具体主题，而非订阅时传入的模式。
+	Topic   string
+	Payload []byte
+	// Ack 确认已处理该消息，仅在底层传输支持显式确认（如 Redis Streams 消费组）时非空。
+	Ack func() error
+}
+
+// Broker 是事件总线的传输层抽象，使 Publish/Subscribe 能够跨越进程边界，
+// 而不是像默认实现那样只能在同一个进程的 Go channel 之间投递。
+type Broker interface {
+	// Publish 把已编码的事件发布到 topic。
+	Publish(topic string, payload []byte) error
+	// Subscribe 让 receiver 开始接收 topic（可以是通配符主题）上的消息。
+	// 同一个 receiver 对同一个 topic 只能订阅一次，重复订阅前必须先 Unsubscribe。
+	Subscribe(topic string, receiver chan<- BrokerMessage) error
+	// Unsubscribe 取消 receiver 对 topic 的订阅；当某个 topic 已无任何订阅者时，
+	// 该 topic 底层占用的传输资源（如 Redis 连接/消费组循环）应被释放。
+	Unsubscribe(topic string, receiver chan<- BrokerMessage) error
+	Close() error
+}
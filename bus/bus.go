@@ -1,27 +1,95 @@
 package bus
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"sync"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type (
 	Bus interface {
 		Publish(topic string, data interface{})
-		Subscribe(topic string, receiver chan Event)
+		Subscribe(topic string, receiver chan Event, opts ...SubscribeOption) Subscription
+	}
+
+	// Subscription 是 Subscribe 返回的句柄，用于取消订阅、观察连接是否被总线强制断开，
+	// 以及查看该订阅的投递/丢弃统计。
+	Subscription interface {
+		Unsubscribe()
+		Err() <-chan error
+		Stats() SubscriptionStats
+	}
+
+	// SubscriptionStats 记录一个订阅累计的投递与丢弃次数。
+	SubscriptionStats struct {
+		Delivered uint64
+		Dropped   uint64
 	}
 
+	// BackpressurePolicy 决定订阅者消费过慢、receiver channel 已满时 Publish 的行为。
+	BackpressurePolicy int
+
+	// SubscribeOptions 配置单个订阅的背压策略。
+	SubscribeOptions struct {
+		Backpressure BackpressurePolicy
+		// MaxMissed 仅在 Backpressure 为 SlowConsumerDisconnect 时生效：
+		// 连续 MaxMissed 次投递失败后自动取消该订阅。
+		MaxMissed int
+	}
+
+	// SubscribeOption 用于以函数式选项配置 SubscribeOptions。
+	SubscribeOption func(*SubscribeOptions)
+
 	// 事件
 	Event struct {
-		Topic string      // 事件主题
-		Data  interface{} // 事件数据
+		Topic        string            // 事件主题
+		Data         interface{}       // 事件数据
+		Ack          func() error      // 确认已处理该事件，仅在 at-least-once 传输（如 Redis Streams）下非空
+		TraceContext map[string]string // 生产者链路上下文，供跨进程（如 Redis）消费者恢复父级 span
 	}
 
 	bus struct {
-		subscribers map[string][]eventChannel
-		lock        sync.RWMutex
+		lock   sync.RWMutex
+		subs   map[uint64]*subscription
+		nextID uint64
+		tracer trace.Tracer
+		meter  metric.Meter
 	}
 
-	eventChannel chan Event
+	subscription struct {
+		id       uint64
+		topic    string
+		receiver chan Event
+		opts     SubscribeOptions
+		bus      *bus
+		errCh    chan error
+
+		// onDisconnect 在 SlowConsumerDisconnect 自动断开该订阅时被调用，
+		// 供上层（如 redisBus）在此之外释放自己额外持有的资源，默认 nil。
+		onDisconnect func()
+
+		mu        sync.Mutex
+		missed    int
+		delivered uint64
+		dropped   uint64
+	}
+)
+
+const (
+	// Block 在 receiver channel 已满时阻塞投递，直到订阅者消费，是历史上的默认行为。
+	// 阻塞发生在投递 goroutine 中（本地总线为 Publish 启动的 goroutine），Publish 本身不等待。
+	Block BackpressurePolicy = iota
+	// DropNewest 在 receiver channel 已满时丢弃本次要投递的新事件。
+	DropNewest
+	// DropOldest 在 receiver channel 已满时丢弃队首最旧的事件，为新事件腾出空间。
+	DropOldest
+	// SlowConsumerDisconnect 在 receiver channel 已满时丢弃事件，
+	// 连续丢弃达到 SubscribeOptions.MaxMissed 次后自动取消该订阅。
+	SlowConsumerDisconnect
 )
 
 var _bus = New()
@@ -30,35 +98,222 @@ func Publish(topic string, data interface{}) {
 	_bus.Publish(topic, data)
 }
 
-func Subscribe(topic string, receiver chan Event) {
-	_bus.Subscribe(topic, receiver)
+func Subscribe(topic string, receiver chan Event, opts ...SubscribeOption) Subscription {
+	return _bus.Subscribe(topic, receiver, opts...)
 }
 
-func New() Bus {
-	return &bus{
-		subscribers: make(map[string][]eventChannel),
+// WithBackpressure 设置该订阅在 receiver channel 已满时的行为，默认 Block。
+func WithBackpressure(policy BackpressurePolicy) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.Backpressure = policy
 	}
 }
 
+// WithMaxMissed 设置 SlowConsumerDisconnect 策略下、断开前允许的最大连续丢弃次数，默认 1。
+func WithMaxMissed(n int) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.MaxMissed = n
+	}
+}
+
+func New(opts ...Option) Bus {
+	b := &bus{
+		subs: make(map[uint64]*subscription),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
 func (b *bus) Publish(topic string, data interface{}) {
+	endSpan, traceContext := b.startProducerSpan(context.Background(), topic)
+	defer endSpan()
+
+	event := Event{Topic: topic, Data: data, TraceContext: traceContext}
+
+	matches := b.matching(topic)
+	if len(matches) == 0 {
+		return
+	}
+
+	go b.dispatchLocal(matches, event)
+}
+
+// dispatchLocal 把 event 投递给已匹配出的订阅者，每个订阅者按自己的 BackpressurePolicy 处理。
+func (b *bus) dispatchLocal(subs []*subscription, event Event) {
+	for _, sub := range subs {
+		endDeliver := b.startConsumerSpan(event.Topic, event.TraceContext)
+		sub.deliver(event)
+		endDeliver()
+	}
+}
+
+func (b *bus) matching(topic string) []*subscription {
 	b.lock.RLock()
 	defer b.lock.RUnlock()
-	if chs, ok := b.subscribers[topic]; ok {
-		channels := append([]eventChannel{}, chs...)
-		go func(data Event, channels []eventChannel) {
-			for _, ch := range channels {
-				ch <- data
-			}
-		}(Event{Data: data, Topic: topic}, channels)
+
+	matches := make([]*subscription, 0, len(b.subs))
+	for _, sub := range b.subs {
+		if matchTopic(sub.topic, topic) {
+			matches = append(matches, sub)
+		}
+	}
+
+	return matches
+}
+
+func (b *bus) Subscribe(topic string, receiver chan Event, opts ...SubscribeOption) Subscription {
+	options := SubscribeOptions{Backpressure: Block, MaxMissed: 1}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.nextID++
+	sub := &subscription{
+		id:       b.nextID,
+		topic:    topic,
+		receiver: receiver,
+		opts:     options,
+		bus:      b,
+		errCh:    make(chan error, 1),
 	}
+	b.subs[sub.id] = sub
+
+	return sub
 }
 
-func (b *bus) Subscribe(topic string, receiver chan Event) {
+func (b *bus) unsubscribe(id uint64) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
-	if prev, ok := b.subscribers[topic]; ok {
-		b.subscribers[topic] = append(prev, receiver)
-	} else {
-		b.subscribers[topic] = append([]eventChannel{}, receiver)
+	delete(b.subs, id)
+}
+
+func (s *subscription) Unsubscribe() {
+	s.bus.unsubscribe(s.id)
+}
+
+func (s *subscription) Err() <-chan error {
+	return s.errCh
+}
+
+func (s *subscription) Stats() SubscriptionStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SubscriptionStats{Delivered: s.delivered, Dropped: s.dropped}
+}
+
+func (s *subscription) deliver(event Event) {
+	switch s.opts.Backpressure {
+	case DropNewest:
+		select {
+		case s.receiver <- event:
+			s.recordDelivered()
+		default:
+			s.recordDropped()
+		}
+	case DropOldest:
+		select {
+		case s.receiver <- event:
+			s.recordDelivered()
+			return
+		default:
+		}
+
+		select {
+		case <-s.receiver:
+		default:
+		}
+
+		select {
+		case s.receiver <- event:
+			s.recordDelivered()
+		default:
+			s.recordDropped()
+		}
+	case SlowConsumerDisconnect:
+		select {
+		case s.receiver <- event:
+			s.recordDelivered()
+			s.resetMissed()
+		default:
+			s.recordDropped()
+			if s.incrMissed() {
+				s.bus.unsubscribe(s.id)
+				if s.onDisconnect != nil {
+					s.onDisconnect()
+				}
+				select {
+				case s.errCh <- fmt.Errorf("bus: subscription to %q disconnected after %d missed sends", s.topic, s.opts.MaxMissed):
+				default:
+				}
+			}
+		}
+	default: // Block
+		s.receiver <- event
+		s.recordDelivered()
 	}
 }
+
+func (s *subscription) recordDelivered() {
+	s.mu.Lock()
+	s.delivered++
+	s.mu.Unlock()
+}
+
+func (s *subscription) recordDropped() {
+	s.mu.Lock()
+	s.dropped++
+	s.mu.Unlock()
+}
+
+func (s *subscription) resetMissed() {
+	s.mu.Lock()
+	s.missed = 0
+	s.mu.Unlock()
+}
+
+// incrMissed 增加连续丢弃计数，达到 MaxMissed 时返回 true。
+func (s *subscription) incrMissed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.missed++
+	return s.missed >= s.opts.MaxMissed
+}
+
+// matchTopic 按 MQTT 规则匹配主题："+" 匹配单个层级，"#" 匹配其后全部层级（只能出现在末尾）。
+func matchTopic(pattern, topic string) bool {
+	if pattern == topic {
+		return true
+	}
+
+	patternSegs := strings.Split(pattern, "/")
+	topicSegs := strings.Split(topic, "/")
+
+	i := 0
+	for ; i < len(patternSegs); i++ {
+		if patternSegs[i] == "#" {
+			return true
+		}
+
+		if i >= len(topicSegs) {
+			return false
+		}
+
+		if patternSegs[i] == "+" {
+			continue
+		}
+
+		if patternSegs[i] != topicSegs[i] {
+			return false
+		}
+	}
+
+	return i == len(topicSegs)
+}
@@ -0,0 +1,20 @@
+package bus
+
+import "encoding/json"
+
+type (
+	// Codec 负责在跨进程传输前后序列化/反序列化 Event.Data。
+	Codec interface {
+		Marshal(v interface{}) ([]byte, error)
+		Unmarshal(data []byte, v interface{}) error
+	}
+
+	jsonCodec struct{}
+)
+
+// JSONCodec 是默认编解码器。
+var JSONCodec Codec = jsonCodec{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
@@ -0,0 +1,424 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// RedisMode 选择 Redis 传输所使用的原语。
+	RedisMode int
+
+	// RedisOptions 配置基于 Redis 的跨进程事件总线。
+	RedisOptions struct {
+		Client   *redis.Client
+		Mode     RedisMode // 默认 RedisModePubSub
+		Codec    Codec     // 默认 JSONCodec
+		Group    string    // RedisModeStream 下的消费组名称，留空则每个实例独立消费
+		Consumer string    // RedisModeStream 下的消费者名称，留空则随机生成
+		Tracer   trace.Tracer
+		Meter    metric.Meter
+	}
+
+	// envelope 是通过 Redis 传输的信封，携带生产者的链路上下文以便跨进程消费者恢复父级 span。
+	envelope struct {
+		Data         interface{}       `json:"data"`
+		TraceContext map[string]string `json:"trace_context,omitempty"`
+	}
+
+	redisBus struct {
+		bus
+		broker Broker
+		codec  Codec
+	}
+
+	// redisSubscription 在取消订阅时同时释放本地订阅记录和底层 Broker 连接。
+	// raw 永不由订阅方关闭——它由 broker 并发地写入，关闭它会在 Unsubscribe
+	// 与一次正在发生的 fanOut 竞争时导致 send on closed channel；forward 改为
+	// 通过 done 得知何时停止读取，raw 随两侧都不再引用后被 GC 回收。
+	redisSubscription struct {
+		Subscription
+		topic  string
+		raw    chan BrokerMessage
+		done   chan struct{}
+		broker Broker
+		once   sync.Once
+	}
+)
+
+const (
+	// RedisModePubSub 使用 Redis Pub/Sub，至多一次投递，不持久化。
+	RedisModePubSub RedisMode = iota
+	// RedisModeStream 使用 Redis Streams + 消费组，至少一次投递，支持 ack。
+	RedisModeStream
+)
+
+// NewRedis 返回一个以 Redis 作为跨进程传输层的 Bus：Publish 把事件写到 Redis，
+// Subscribe 在本地注册的同时通过 Broker 订阅 Redis，收到的事件被解码后直接投递给该订阅者。
+func NewRedis(opts RedisOptions) Bus {
+	if opts.Codec == nil {
+		opts.Codec = JSONCodec
+	}
+
+	return &redisBus{
+		bus: bus{
+			subs:   make(map[uint64]*subscription),
+			tracer: opts.Tracer,
+			meter:  opts.Meter,
+		},
+		broker: newRedisBroker(opts),
+		codec:  opts.Codec,
+	}
+}
+
+func (b *redisBus) Publish(topic string, data interface{}) {
+	endSpan, traceContext := b.startProducerSpan(context.Background(), topic)
+	defer endSpan()
+
+	payload, err := b.codec.Marshal(envelope{Data: data, TraceContext: traceContext})
+	if err != nil {
+		log.Printf("bus: marshal event for topic %q error: %v", topic, err)
+		return
+	}
+
+	if err := b.broker.Publish(topic, payload); err != nil {
+		log.Printf("bus: publish topic %q error: %v", topic, err)
+	}
+}
+
+// Subscribe 在本地注册订阅，并让 Broker 把 topic（RedisModePubSub 下包括使用通配符主题的）上收到的消息
+// 转发给这条订阅专属的 channel——多个订阅者共享同一条 Broker 连接，
+// 但每个订阅者的确认（Ack）互不影响彼此。RedisModeStream 下 topic 按字面作为 stream 键，不做通配符匹配。
+func (b *redisBus) Subscribe(topic string, receiver chan Event, opts ...SubscribeOption) Subscription {
+	sub := b.bus.Subscribe(topic, receiver, opts...).(*subscription)
+
+	raw := make(chan BrokerMessage, 64)
+	if err := b.broker.Subscribe(topic, raw); err != nil {
+		log.Printf("bus: subscribe topic %q error: %v", topic, err)
+	}
+
+	rs := &redisSubscription{Subscription: sub, topic: topic, raw: raw, done: make(chan struct{}), broker: b.broker}
+	// SlowConsumerDisconnect 会绕过 rs.Unsubscribe 直接从 bus.subs 里摘除该订阅，
+	// 这里让它同样触发 broker 侧的取消订阅，否则 forward goroutine 和 Redis 连接会泄漏。
+	sub.onDisconnect = rs.teardownBroker
+
+	go b.forward(sub, raw, rs.done)
+
+	return rs
+}
+
+func (b *redisBus) forward(sub *subscription, raw chan BrokerMessage, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case msg := <-raw:
+			var env envelope
+			if err := b.codec.Unmarshal(msg.Payload, &env); err != nil {
+				log.Printf("bus: unmarshal event for topic %q error: %v", msg.Topic, err)
+				continue
+			}
+
+			endSpan := b.startConsumerSpan(msg.Topic, env.TraceContext)
+			sub.deliver(Event{Topic: msg.Topic, Data: env.Data, Ack: msg.Ack, TraceContext: env.TraceContext})
+			endSpan()
+		}
+	}
+}
+
+func (b *redisBus) Close() error {
+	return b.broker.Close()
+}
+
+func (s *redisSubscription) Unsubscribe() {
+	s.Subscription.Unsubscribe()
+	s.teardownBroker()
+}
+
+// teardownBroker 取消该订阅在 Broker 上的注册并停止其 forward goroutine，
+// 无论触发方是显式的 Unsubscribe 调用还是 SlowConsumerDisconnect 的自动断开，都只执行一次。
+func (s *redisSubscription) teardownBroker() {
+	s.once.Do(func() {
+		if err := s.broker.Unsubscribe(s.topic, s.raw); err != nil {
+			log.Printf("bus: unsubscribe topic %q error: %v", s.topic, err)
+		}
+		close(s.done)
+	})
+}
+
+// redisBroker 用 Redis Pub/Sub 或 Streams 实现 Broker：同一个 topic 的所有 receiver
+// 共享一个监听 goroutine（一条 Redis 连接/一个消费组循环），当最后一个 receiver 取消订阅时该
+// 监听 goroutine 会被停止。通配符主题只在 Pub/Sub 模式下（经 PSUBSCRIBE）生效，
+// Streams 模式下 topic 直接作为 stream 键使用。
+type redisBroker struct {
+	client   *redis.Client
+	mode     RedisMode
+	group    string
+	consumer string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock   sync.Mutex
+	topics map[string]*brokerTopic
+}
+
+// brokerTopic 记录某个 topic 当前存活的 receiver，以及停止其监听 goroutine 的方法。
+type brokerTopic struct {
+	cancel    context.CancelFunc
+	receivers map[chan<- BrokerMessage]bool
+}
+
+func newRedisBroker(opts RedisOptions) *redisBroker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	consumer := opts.Consumer
+	if consumer == "" {
+		consumer = randomConsumerName()
+	}
+
+	return &redisBroker{
+		client:   opts.Client,
+		mode:     opts.Mode,
+		group:    opts.Group,
+		consumer: consumer,
+		ctx:      ctx,
+		cancel:   cancel,
+		topics:   make(map[string]*brokerTopic),
+	}
+}
+
+func (rb *redisBroker) Publish(topic string, payload []byte) error {
+	if rb.mode == RedisModeStream {
+		return rb.client.XAdd(rb.ctx, &redis.XAddArgs{
+			Stream: topic,
+			Values: map[string]interface{}{"data": payload},
+		}).Err()
+	}
+
+	return rb.client.Publish(rb.ctx, topic, payload).Err()
+}
+
+func (rb *redisBroker) Subscribe(topic string, receiver chan<- BrokerMessage) error {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	t, ok := rb.topics[topic]
+	if !ok {
+		ctx, cancel := context.WithCancel(rb.ctx)
+		t = &brokerTopic{cancel: cancel, receivers: make(map[chan<- BrokerMessage]bool)}
+		rb.topics[topic] = t
+
+		switch rb.mode {
+		case RedisModeStream:
+			go rb.listenStream(ctx, topic, t)
+		default:
+			go rb.listenPubSub(ctx, topic, t)
+		}
+	}
+
+	t.receivers[receiver] = true
+
+	return nil
+}
+
+func (rb *redisBroker) Unsubscribe(topic string, receiver chan<- BrokerMessage) error {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	t, ok := rb.topics[topic]
+	if !ok {
+		return nil
+	}
+
+	delete(t.receivers, receiver)
+	if len(t.receivers) == 0 {
+		t.cancel()
+		delete(rb.topics, topic)
+	}
+
+	return nil
+}
+
+func (rb *redisBroker) Close() error {
+	rb.cancel()
+	return nil
+}
+
+// fanOut 把一条消息投递给 topic 当前存活的所有 receiver。Stream 模式下，
+// 消息的真正确认（XAck）会被推迟到所有 receiver 都调用过各自的 Ack 之后才发生，
+// 这样一个 receiver 提前确认不会导致另一个还未处理完的 receiver 被误判为已完成。
+func (rb *redisBroker) fanOut(t *brokerTopic, msg BrokerMessage) {
+	rb.lock.Lock()
+	receivers := make([]chan<- BrokerMessage, 0, len(t.receivers))
+	for r := range t.receivers {
+		receivers = append(receivers, r)
+	}
+	rb.lock.Unlock()
+
+	if len(receivers) == 0 {
+		return
+	}
+
+	if msg.Ack != nil {
+		msg.Ack = ackAfterAll(msg.Ack, len(receivers))
+	}
+
+	for _, r := range receivers {
+		select {
+		case r <- msg:
+		case <-rb.ctx.Done():
+			return
+		}
+	}
+}
+
+func (rb *redisBroker) listenPubSub(ctx context.Context, topic string, t *brokerTopic) {
+	var sub *redis.PubSub
+	if hasWildcard(topic) {
+		sub = rb.client.PSubscribe(ctx, toRedisPattern(topic))
+	} else {
+		sub = rb.client.Subscribe(ctx, topic)
+	}
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			if !matchTopic(topic, msg.Channel) {
+				continue
+			}
+
+			rb.fanOut(t, BrokerMessage{Topic: msg.Channel, Payload: []byte(msg.Payload)})
+		}
+	}
+}
+
+func (rb *redisBroker) listenStream(ctx context.Context, topic string, t *brokerTopic) {
+	group := rb.group
+	if group == "" {
+		group = "bus"
+	}
+
+	if err := rb.client.XGroupCreateMkStream(ctx, topic, group, "$").Err(); err != nil &&
+		!isBusyGroupErr(err) {
+		log.Printf("bus: redis XGROUP CREATE topic %q error: %v", topic, err)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		streams, err := rb.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: rb.consumer,
+			Streams:  []string{topic, ">"},
+			Count:    10,
+			Block:    0,
+		}).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Printf("bus: redis XREADGROUP topic %q error: %v", topic, err)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				payload, _ := msg.Values["data"].(string)
+				id := msg.ID
+				ack := func() error {
+					return rb.client.XAck(rb.ctx, topic, group, id).Err()
+				}
+
+				rb.fanOut(t, BrokerMessage{Topic: topic, Payload: []byte(payload), Ack: ack})
+			}
+		}
+	}
+}
+
+// ackAfterAll 包装底层 ack，使其只在被调用满 n 次（对应 n 个 receiver 各自确认一次）
+// 之后才真正执行一次，且只执行一次。
+func ackAfterAll(ack func() error, n int) func() error {
+	var (
+		mu        sync.Mutex
+		remaining = n
+		once      sync.Once
+		err       error
+	)
+
+	return func() error {
+		mu.Lock()
+		remaining--
+		done := remaining <= 0
+		mu.Unlock()
+
+		if !done {
+			return nil
+		}
+
+		once.Do(func() { err = ack() })
+
+		return err
+	}
+}
+
+// hasWildcard 判断 topic 是否包含 MQTT 风格的通配符（"+" 或 "#"）。
+func hasWildcard(topic string) bool {
+	return strings.ContainsAny(topic, "+#")
+}
+
+// toRedisPattern 把 MQTT 风格的通配符主题转换成 Redis PSUBSCRIBE 使用的 glob 模式。
+// Redis 的 glob 语法无法像 "+" 那样把匹配限制在单个层级内，因此这里生成的是一个更宽松、
+// 可能过度匹配的模式；真正的层级边界由 matchTopic 在消息送达后按实际 channel 二次过滤。
+func toRedisPattern(topic string) string {
+	segs := strings.Split(topic, "/")
+
+	for i, seg := range segs {
+		if seg == "#" {
+			return strings.Join(append(segs[:i], "*"), "/")
+		}
+		if seg == "+" {
+			segs[i] = "*"
+		}
+	}
+
+	return strings.Join(segs, "/")
+}
+
+// randomConsumerName 为未显式配置 Consumer 的实例生成一个跨进程唯一的消费者名，
+// 避免同一个消费组内的多个副本共用同一个 consumer 名称而互相抢占彼此的消息。
+func randomConsumerName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "bus"
+	}
+
+	return fmt.Sprintf("%s-%d-%d", host, os.Getpid(), rand.Int63())
+}
+
+func isBusyGroupErr(err error) bool {
+	return err != nil && err.Error() == "BUSYGROUP Consumer Group name already exists"
+}
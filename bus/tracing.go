@@ -0,0 +1,78 @@
+package bus
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option 配置 New 创建的 Bus，目前用于按需接入 OTel 追踪与指标。
+type Option func(*bus)
+
+// WithTracer 为 Publish/Subscribe 开启生产者/消费者 span，并通过 Event.TraceContext
+// 跨进程（如 Redis）传播链路上下文，默认不开启。
+func WithTracer(tracer trace.Tracer) Option {
+	return func(b *bus) {
+		b.tracer = tracer
+	}
+}
+
+// WithMeter 为 Bus 启用 bus_queue_depth 观测指标（各 topic 下订阅者 channel 的积压长度），默认不开启。
+func WithMeter(meter metric.Meter) Option {
+	return func(b *bus) {
+		gauge, err := meter.Int64ObservableGauge("bus_queue_depth")
+		if err != nil {
+			return
+		}
+
+		_, _ = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+			b.lock.RLock()
+			defer b.lock.RUnlock()
+
+			for _, sub := range b.subs {
+				o.ObserveInt64(gauge, int64(len(sub.receiver)), metric.WithAttributes(attribute.String("topic", sub.topic)))
+			}
+
+			return nil
+		}, gauge)
+	}
+}
+
+// startProducerSpan 打开一个生产者 span，并返回可注入到 Event.TraceContext 的传播载体，
+// 未配置 tracer 时返回 nil，Publish 不产生任何开销。
+func (b *bus) startProducerSpan(ctx context.Context, topic string) (func(), map[string]string) {
+	if b.tracer == nil {
+		return func() {}, nil
+	}
+
+	ctx, span := b.tracer.Start(ctx, "bus.Publish", trace.WithAttributes(
+		attribute.String("messaging.destination", topic),
+	))
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	return span.End, map[string]string(carrier)
+}
+
+// startConsumerSpan 打开一个消费者 span，如果 traceContext 非空则先从中恢复父级链路。
+func (b *bus) startConsumerSpan(topic string, traceContext map[string]string) func() {
+	if b.tracer == nil {
+		return func() {}
+	}
+
+	ctx := context.Background()
+	if traceContext != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(traceContext))
+	}
+
+	_, span := b.tracer.Start(ctx, "bus.Subscribe", trace.WithAttributes(
+		attribute.String("messaging.destination", topic),
+	))
+
+	return span.End
+}
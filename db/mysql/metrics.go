@@ -0,0 +1,42 @@
+package mysql
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gorm.io/gorm"
+)
+
+// MetricsHook 返回一个 Hook，为实例的连接池注册 in-use/idle 连接数的可观测 Gauge。
+func MetricsHook(meter metric.Meter) Hook {
+	return func(db *gorm.DB, option *Option) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+
+		inUse, err := meter.Int64ObservableGauge("mysql_pool_connections_in_use")
+		if err != nil {
+			return err
+		}
+
+		idle, err := meter.Int64ObservableGauge("mysql_pool_connections_idle")
+		if err != nil {
+			return err
+		}
+
+		// db.Name() 返回的是 dialector 名称（恒为 "mysql"），无法区分实例，
+		// 这里用配置里的 DBName 才能按实例区分指标。
+		dbName := attribute.String("db.name", option.DBName)
+
+		_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+			stats := sqlDB.Stats()
+			o.ObserveInt64(inUse, int64(stats.InUse), metric.WithAttributes(dbName))
+			o.ObserveInt64(idle, int64(stats.Idle), metric.WithAttributes(dbName))
+			return nil
+		}, inUse, idle)
+
+		return err
+	}
+}
@@ -1,33 +1,55 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 type (
+	// ReplicaPolicy 决定读请求在多个只读副本之间如何分配。
+	ReplicaPolicy string
+
 	Option struct {
-		Host            string `json:"host"`              // HOST
-		Port            int    `json:"port"`              // 端口
-		DBName          string `json:"db_name"`           // 数据库名称
-		Username        string `json:"username"`          // 用户名
-		Password        string `json:"password"`          // 密码
-		Params          string `json:"params"`            // 连接参数
-		MaxIdleConns    int    `json:"max_idle_conns"`    // 连接池：最大空闲连接数量
-		MaxOpenConns    int    `json:"max_open_conns"`    // 连接池：最大打开连接数量
-		ConnMaxLifetime int    `json:"conn_max_lifetime"` // 连接池：连接最大可复用时间（单位：秒）
+		Host                string        `json:"host"`                  // HOST
+		Port                int           `json:"port"`                  // 端口
+		DBName              string        `json:"db_name"`               // 数据库名称
+		Username            string        `json:"username"`              // 用户名
+		Password            string        `json:"password"`              // 密码
+		Params              string        `json:"params"`                // 连接参数
+		MaxIdleConns        int           `json:"max_idle_conns"`        // 连接池：最大空闲连接数量
+		MaxOpenConns        int           `json:"max_open_conns"`        // 连接池：最大打开连接数量
+		ConnMaxLifetime     int           `json:"conn_max_lifetime"`     // 连接池：连接最大可复用时间（单位：秒）
+		Replicas            []string      `json:"replicas"`              // 只读副本 DSN 列表，为空则不启用读写分离
+		ReplicaPolicy       ReplicaPolicy `json:"replica_policy"`        // 只读副本选择策略，默认 PolicyRandom
+		ReplicaWeights      []int         `json:"replica_weights"`       // PolicyWeighted 下每个副本的权重，需与 Replicas 等长
+		HealthCheckInterval int           `json:"health_check_interval"` // 健康检查周期（单位：秒），默认 30，<0 关闭
 	}
+
+	// Hook 在实例创建时注册到 *gorm.DB 上的横切逻辑，例如慢查询日志、链路追踪。
+	// option 是该实例对应的配置，用于需要区分具体实例（如按 db.name 打标签）的 Hook。
+	Hook func(db *gorm.DB, option *Option) error
+)
+
+const (
+	PolicyRandom     ReplicaPolicy = "random"
+	PolicyRoundRobin ReplicaPolicy = "round_robin"
+	PolicyWeighted   ReplicaPolicy = "weighted"
 )
 
 var (
 	_options   = make(map[string]*Option)
 	_defaultDB = ""
 	_instances sync.Map
+	_hooks     []Hook
+	_hooksLock sync.Mutex
 )
 
 func Init(options ...Option) {
@@ -40,6 +62,14 @@ func Init(options ...Option) {
 	}
 }
 
+// WithHooks 注册在每个新建实例上执行的横切逻辑（如慢查询日志、追踪埋点）。
+// 必须在首次调用 DB/DefaultDB 触发实例创建之前调用才能生效。
+func WithHooks(hooks ...Hook) {
+	_hooksLock.Lock()
+	defer _hooksLock.Unlock()
+	_hooks = append(_hooks, hooks...)
+}
+
 func DefaultDB() (*gorm.DB, error) {
 	return DB(_defaultDB)
 }
@@ -64,15 +94,53 @@ func DB(dbName string) (*gorm.DB, error) {
 	return instance.(*gorm.DB), nil
 }
 
+// Transaction 在事务中执行 fn：出现 panic 会被恢复并回滚，panic 值包装成 error 返回（不再重新抛出）；
+// fn 返回 error 会回滚，
+// ctx 被取消时事务也会回滚。
+func Transaction(ctx context.Context, dbName string, fn func(tx *gorm.DB) error) error {
+	db, err := DB(dbName)
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("mysql: transaction panic: %v", rec)
+			}
+		}()
+
+		return fn(tx)
+	})
+}
+
 func createInstance(option *Option) (*gorm.DB, error) {
-	// user:pass@tcp(127.0.0.1:3306)/dbname?charset=utf8mb4&collation=utf8mb4_general_ci&parseTime=true&loc=Local
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s",
-		option.Username,
-		option.Password,
-		option.Host,
-		option.Port,
-		option.DBName,
-		option.Params)
+	db, err := dial(option)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := registerResolver(db, option); err != nil {
+		return nil, err
+	}
+
+	_hooksLock.Lock()
+	hooks := append([]Hook{}, _hooks...)
+	_hooksLock.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook(db, option); err != nil {
+			return nil, err
+		}
+	}
+
+	go healthCheck(option)
+
+	return db, nil
+}
+
+func dial(option *Option) (*gorm.DB, error) {
+	dsn := buildDSN(option.Username, option.Password, option.Host, option.Port, option.DBName, option.Params)
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
@@ -91,3 +159,107 @@ func createInstance(option *Option) (*gorm.DB, error) {
 
 	return db, nil
 }
+
+// buildDSN 拼接 user:pass@tcp(host:port)/dbname?params，
+// params 原样作为查询串，如 charset=utf8mb4&collation=utf8mb4_general_ci&parseTime=true&loc=Local
+func buildDSN(username, password, host string, port int, dbName, params string) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s", username, password, host, port, dbName, params)
+}
+
+// registerResolver 在配置了只读副本时为 db 挂上 dbresolver 插件，让 SELECT 自动路由到副本、写操作留在主库。
+func registerResolver(db *gorm.DB, option *Option) error {
+	if len(option.Replicas) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(option.Replicas))
+	for _, dsn := range option.Replicas {
+		replicas = append(replicas, mysql.Open(dsn))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   replicaPolicy(option.ReplicaPolicy, option.ReplicaWeights),
+	})
+
+	return db.Use(resolver)
+}
+
+func replicaPolicy(policy ReplicaPolicy, weights []int) dbresolver.Policy {
+	switch policy {
+	case PolicyRoundRobin:
+		return dbresolver.RoundRobinPolicy()
+	case PolicyWeighted:
+		return &weightedPolicy{weights: weights}
+	default:
+		return dbresolver.RandomPolicy{}
+	}
+}
+
+// weightedPolicy 按配置的权重在多个只读副本连接池之间加权随机选择。
+// 权重数量与副本数量不一致（未配置或配置错误）时退化为等权随机。
+type weightedPolicy struct {
+	weights []int
+}
+
+func (p *weightedPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	if len(p.weights) != len(connPools) {
+		return connPools[rand.Intn(len(connPools))]
+	}
+
+	total := 0
+	for _, w := range p.weights {
+		total += w
+	}
+
+	if total <= 0 {
+		return connPools[rand.Intn(len(connPools))]
+	}
+
+	target := rand.Intn(total)
+	for i, w := range p.weights {
+		target -= w
+		if target < 0 {
+			return connPools[i]
+		}
+	}
+
+	return connPools[len(connPools)-1]
+}
+
+// healthCheck 周期性地 Ping 实例，连接池损坏时主动重新建立连接，
+// 避免 _instances 中缓存的实例在数据库短暂不可用后永久失效。
+func healthCheck(option *Option) {
+	interval := 30 * time.Second
+	if option.HealthCheckInterval < 0 {
+		return
+	}
+	if option.HealthCheckInterval > 0 {
+		interval = time.Duration(option.HealthCheckInterval) * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		instance, ok := _instances.Load(option.DBName)
+		if !ok {
+			return
+		}
+
+		db := instance.(*gorm.DB)
+		sqlDB, err := db.DB()
+		if err == nil && sqlDB.Ping() == nil {
+			continue
+		}
+
+		fresh, err := createInstance(option)
+		if err != nil {
+			continue
+		}
+
+		_instances.Store(option.DBName, fresh)
+
+		return
+	}
+}
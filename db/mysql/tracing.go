@@ -0,0 +1,111 @@
+package mysql
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	spanInstanceKey  = "observability:span"
+	startInstanceKey = "observability:span_start"
+)
+
+// TracingHook 返回一个 Hook，把它传给 WithHooks 后，createInstance 会在每个新建实例上
+// 注册一个 GORM 插件，为每条执行的 SQL 语句（create/query/update/delete/row/raw）打开一个 span。
+func TracingHook(tracer trace.Tracer) Hook {
+	return func(db *gorm.DB, _ *Option) error {
+		return db.Use(&tracingPlugin{tracer: tracer})
+	}
+}
+
+type tracingPlugin struct {
+	tracer trace.Tracer
+}
+
+func (p *tracingPlugin) Name() string { return "observability:tracing" }
+
+func (p *tracingPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:before_create").Register("observability:before_create", p.before("create")); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:after_create").Register("observability:after_create", p.after); err != nil {
+		return err
+	}
+
+	if err := db.Callback().Query().Before("gorm:query").Register("observability:before_query", p.before("query")); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("gorm:after_query").Register("observability:after_query", p.after); err != nil {
+		return err
+	}
+
+	if err := db.Callback().Update().Before("gorm:before_update").Register("observability:before_update", p.before("update")); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:after_update").Register("observability:after_update", p.after); err != nil {
+		return err
+	}
+
+	if err := db.Callback().Delete().Before("gorm:before_delete").Register("observability:before_delete", p.before("delete")); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().After("gorm:after_delete").Register("observability:after_delete", p.after); err != nil {
+		return err
+	}
+
+	if err := db.Callback().Row().Before("gorm:row").Register("observability:before_row", p.before("row")); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("observability:after_row", p.after); err != nil {
+		return err
+	}
+
+	if err := db.Callback().Raw().Before("gorm:raw").Register("observability:before_raw", p.before("raw")); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().After("gorm:raw").Register("observability:after_raw", p.after); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *tracingPlugin) before(op string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx, span := p.tracer.Start(tx.Statement.Context, "gorm."+op)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(spanInstanceKey, span)
+		tx.InstanceSet(startInstanceKey, time.Now())
+	}
+}
+
+func (p *tracingPlugin) after(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(attribute.String("db.statement", tx.Statement.SQL.String()))
+
+	if startValue, ok := tx.InstanceGet(startInstanceKey); ok {
+		if start, ok := startValue.(time.Time); ok {
+			span.SetAttributes(attribute.Int64("db.duration_ms", time.Since(start).Milliseconds()))
+		}
+	}
+
+	if tx.Error != nil {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,40 @@
+// Package observability 提供跨 rest/mysql/bus 等包的可观测性接入点：
+// 统一初始化 OTel TracerProvider/MeterProvider，rest 与 bus 再通过自己的
+// WithTracer/WithMeter 选项按需接入，默认不开启；mysql 没有 WithTracer/WithMeter，
+// 通过 mysql.WithHooks(mysql.TracingHook(...), mysql.MetricsHook(...)) 接入。
+package observability
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Options 配置全局 TracerProvider/MeterProvider。
+type Options struct {
+	ServiceName    string
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+}
+
+const defaultServiceName = "hungkuk"
+
+// Init 注册全局 TracerProvider/MeterProvider，并返回以 ServiceName 命名的 Tracer/Meter，
+// 供调用方传给 rest.RESTClient.WithTracer、mysql.WithHooks(mysql.TracingHook(...))、
+// bus.WithTracer 等选项。
+func Init(opts Options) (trace.Tracer, metric.Meter) {
+	if opts.TracerProvider != nil {
+		otel.SetTracerProvider(opts.TracerProvider)
+	}
+
+	if opts.MeterProvider != nil {
+		otel.SetMeterProvider(opts.MeterProvider)
+	}
+
+	name := opts.ServiceName
+	if name == "" {
+		name = defaultServiceName
+	}
+
+	return otel.Tracer(name), otel.Meter(name)
+}
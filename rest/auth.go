@@ -0,0 +1,144 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+type (
+	// AuthProvider 在请求发出前往其中注入鉴权信息，取代手工拼装 Authorization 头。
+	AuthProvider interface {
+		Apply(req *http.Request) error
+	}
+
+	bearerTokenProvider string
+
+	tokenSourceProvider struct {
+		source oauth2.TokenSource
+	}
+
+	// JWTClaims 描述 JWTProvider 签发令牌时携带的声明。
+	JWTClaims struct {
+		Issuer   string
+		Subject  string
+		Audience string
+		TTL      time.Duration
+		Extra    map[string]interface{}
+	}
+
+	// JWTProvider 按需签发并缓存 JWT，临近过期时自动重新签发。
+	JWTProvider struct {
+		method jwt.SigningMethod
+		key    interface{}
+		claims JWTClaims
+
+		mu        sync.Mutex
+		cached    string
+		expiresAt time.Time
+	}
+)
+
+// NewBearerTokenProvider 返回一个始终附带固定 Bearer token 的 AuthProvider。
+func NewBearerTokenProvider(token string) AuthProvider {
+	return bearerTokenProvider(token)
+}
+
+func (t bearerTokenProvider) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// NewTokenSourceProvider 把 oauth2.TokenSource 适配成 AuthProvider，每次请求前调用 source.Token()；
+// 它自身不缓存令牌，缓存与刷新由 source 决定（如 oauth2.ReuseTokenSource）。
+func NewTokenSourceProvider(source oauth2.TokenSource) AuthProvider {
+	return &tokenSourceProvider{source: source}
+}
+
+func (p *tokenSourceProvider) Apply(req *http.Request) error {
+	token, err := p.source.Token()
+	if err != nil {
+		return fmt.Errorf("rest: refresh oauth2 token error: %v", err)
+	}
+
+	token.SetAuthHeader(req)
+
+	return nil
+}
+
+// NewOAuth2ClientCredentialsProvider 返回一个按 OAuth2 client-credentials 模式获取、
+// 缓存并自动刷新 access token 的 AuthProvider。
+func NewOAuth2ClientCredentialsProvider(ctx context.Context, cfg clientcredentials.Config) AuthProvider {
+	return NewTokenSourceProvider(cfg.TokenSource(ctx))
+}
+
+// NewHS256JWTProvider 返回一个使用对称密钥签发 HS256 JWT 的 AuthProvider。
+func NewHS256JWTProvider(secret []byte, claims JWTClaims) *JWTProvider {
+	return &JWTProvider{method: jwt.SigningMethodHS256, key: secret, claims: claims}
+}
+
+// NewRS256JWTProvider 返回一个使用 RSA 私钥签发 RS256 JWT 的 AuthProvider。
+func NewRS256JWTProvider(key interface{}, claims JWTClaims) *JWTProvider {
+	return &JWTProvider{method: jwt.SigningMethodRS256, key: key, claims: claims}
+}
+
+func (p *JWTProvider) Apply(req *http.Request) error {
+	token, err := p.token()
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+// token 返回缓存的令牌，临近过期（30 秒内）或尚未签发时重新签发。
+func (p *JWTProvider) token() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cached != "" && time.Now().Before(p.expiresAt.Add(-30*time.Second)) {
+		return p.cached, nil
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(p.claims.TTL)
+
+	claims := jwt.MapClaims{
+		"iat": now.Unix(),
+		"exp": expiresAt.Unix(),
+	}
+
+	if p.claims.Issuer != "" {
+		claims["iss"] = p.claims.Issuer
+	}
+
+	if p.claims.Subject != "" {
+		claims["sub"] = p.claims.Subject
+	}
+
+	if p.claims.Audience != "" {
+		claims["aud"] = p.claims.Audience
+	}
+
+	for k, v := range p.claims.Extra {
+		claims[k] = v
+	}
+
+	signed, err := jwt.NewWithClaims(p.method, claims).SignedString(p.key)
+	if err != nil {
+		return "", fmt.Errorf("rest: sign jwt error: %v", err)
+	}
+
+	p.cached = signed
+	p.expiresAt = expiresAt
+
+	return signed, nil
+}
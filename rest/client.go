@@ -2,11 +2,21 @@ package rest
 
 import (
 	"net/http"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
 )
 
 type (
 	RESTClient interface {
 		SetBasicAuth(username, password string) RESTClient
+		SetBearerToken(token string) RESTClient
+		SetTokenSource(source oauth2.TokenSource) RESTClient
+		SetAuthProvider(provider AuthProvider) RESTClient
+		WithCodec(codec Codec) RESTClient
+		WithTracer(tracer trace.Tracer) RESTClient
+		WithMeter(meter metric.Meter) RESTClient
 		Post() Request
 		Put() Request
 		Get() Request
@@ -15,10 +25,14 @@ type (
 	}
 
 	restClient struct {
-		client   *http.Client
-		baseURL  string
-		username string
-		password string
+		client       *http.Client
+		baseURL      string
+		username     string
+		password     string
+		codec        Codec
+		authProvider AuthProvider
+		tracer       trace.Tracer
+		meter        metric.Meter
 	}
 )
 
@@ -55,12 +69,40 @@ func (rc *restClient) SetBasicAuth(username, password string) RESTClient {
 	return rc
 }
 
+func (rc *restClient) SetBearerToken(token string) RESTClient {
+	rc.authProvider = NewBearerTokenProvider(token)
+	return rc
+}
+
+func (rc *restClient) SetTokenSource(source oauth2.TokenSource) RESTClient {
+	rc.authProvider = NewTokenSourceProvider(source)
+	return rc
+}
+
+func (rc *restClient) SetAuthProvider(provider AuthProvider) RESTClient {
+	rc.authProvider = provider
+	return rc
+}
+
+func (rc *restClient) WithCodec(codec Codec) RESTClient {
+	rc.codec = codec
+	return rc
+}
+
 func (rc *restClient) method(method string) Request {
+	codec := rc.codec
+	if codec == nil {
+		codec = JSONCodec
+	}
+
 	return &request{
-		method:   method,
-		baseURL:  rc.baseURL,
-		client:   rc.client,
-		username: rc.username,
-		password: rc.password,
+		method:       method,
+		baseURL:      rc.baseURL,
+		client:       rc.client,
+		username:     rc.username,
+		password:     rc.password,
+		codec:        codec,
+		authProvider: rc.authProvider,
+		tracing:      newRequestTracer(rc.tracer, rc.meter),
 	}
 }
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	// Codec 负责请求体/响应体的编解码，并声明协商用的 Content-Type。
+	Codec interface {
+		Marshal(v interface{}) ([]byte, error)
+		Unmarshal(data []byte, v interface{}) error
+		ContentType() string
+	}
+
+	jsonCodec     struct{}
+	protobufCodec struct{}
+	msgpackCodec  struct{}
+	xmlCodec      struct{}
+)
+
+var (
+	// JSONCodec 是默认编解码器，行为与历史上硬编码的 encoding/json 一致。
+	JSONCodec Codec = jsonCodec{}
+	// ProtobufCodec 编解码实现了 proto.Message 的类型。
+	ProtobufCodec Codec = protobufCodec{}
+	// MsgpackCodec 使用 msgpack 二进制格式编解码。
+	MsgpackCodec Codec = msgpackCodec{}
+	// XMLCodec 使用 encoding/xml 编解码。
+	XMLCodec Codec = xmlCodec{}
+
+	codecsByContentType = map[string]Codec{
+		JSONCodec.ContentType():     JSONCodec,
+		ProtobufCodec.ContentType(): ProtobufCodec,
+		MsgpackCodec.ContentType():  MsgpackCodec,
+		XMLCodec.ContentType():      XMLCodec,
+	}
+)
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) ContentType() string                        { return "application/json" }
+
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("rest: %T does not implement proto.Message", v)
+	}
+
+	return proto.Marshal(msg)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("rest: %T does not implement proto.Message", v)
+	}
+
+	return proto.Unmarshal(data, msg)
+}
+
+func (protobufCodec) ContentType() string { return "application/x-protobuf" }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error { return msgpack.Unmarshal(data, v) }
+func (msgpackCodec) ContentType() string                        { return "application/x-msgpack" }
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error)      { return xml.Marshal(v) }
+func (xmlCodec) Unmarshal(data []byte, v interface{}) error { return xml.Unmarshal(data, v) }
+func (xmlCodec) ContentType() string                        { return "application/xml" }
+
+// codecForContentType 根据响应的 Content-Type 选择解码器，未知类型时回退到 fallback。
+func codecForContentType(contentType string, fallback Codec) Codec {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fallback
+	}
+
+	if codec, ok := codecsByContentType[mediaType]; ok {
+		return codec
+	}
+
+	return fallback
+}
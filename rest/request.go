@@ -3,7 +3,6 @@ package rest
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -26,6 +25,8 @@ type (
 		WithTimeout(d time.Duration) Request
 		WithMaxRetry(count int) Request
 		WithRetryInterval(d time.Duration) Request
+		WithRetryPolicy(policy RetryPolicy) Request
+		WithCodec(codec Codec) Request
 		SubResourcef(subPath string, args ...interface{}) Request
 		Body(body interface{}) Request
 		Do() Result
@@ -43,9 +44,13 @@ type (
 		subPathArgs   []interface{}
 		retryCount    int
 		retryInterval time.Duration
+		retryPolicy   RetryPolicy
+		codec         Codec
 		timeout       time.Duration
 		username      string
 		password      string
+		authProvider  AuthProvider
+		tracing       requestTracer
 		err           error
 	}
 )
@@ -107,6 +112,16 @@ func (r *request) WithRetryInterval(d time.Duration) Request {
 	return r
 }
 
+func (r *request) WithRetryPolicy(policy RetryPolicy) Request {
+	r.retryPolicy = policy
+	return r
+}
+
+func (r *request) WithCodec(codec Codec) Request {
+	r.codec = codec
+	return r
+}
+
 func (r *request) SubResourcef(subPath string, args ...interface{}) Request {
 	r.subPathArgs = args
 	return r.subResource(subPath)
@@ -124,7 +139,7 @@ func (r *request) Body(body interface{}) Request {
 		return r
 	}
 
-	data, err := json.Marshal(body)
+	data, err := r.codecOrDefault().Marshal(body)
 	if err != nil {
 		r.err = err
 		r.body = []byte("")
@@ -136,6 +151,25 @@ func (r *request) Body(body interface{}) Request {
 	return r
 }
 
+func (r *request) codecOrDefault() Codec {
+	if r.codec == nil {
+		return JSONCodec
+	}
+
+	return r.codec
+}
+
+// applyAuth 优先使用 AuthProvider 注入鉴权信息，未配置时回退到 HTTP basic auth。
+func (r *request) applyAuth(req *http.Request) error {
+	if r.authProvider != nil {
+		return r.authProvider.Apply(req)
+	}
+
+	req.SetBasicAuth(r.username, r.password)
+
+	return nil
+}
+
 func (r *request) wrapURL() *url.URL {
 	finalUrl := &url.URL{}
 	if len(r.baseURL) != 0 {
@@ -176,14 +210,18 @@ func (r *request) Do() Result {
 		return rt
 	}
 
+	if r.retryPolicy != nil {
+		return r.doWithPolicy()
+	}
+
 	retry := false
-	rt, retry = r.tryOnce()
+	rt, retry = r.tryOnce(0)
 	if !retry {
 		return rt
 	}
 
 	for try := 0; try < r.retryCount; try++ {
-		rt, retry = r.tryOnce()
+		rt, retry = r.tryOnce(try + 1)
 		if !retry {
 			return rt
 		}
@@ -194,40 +232,161 @@ func (r *request) Do() Result {
 	return rt
 }
 
-func (r *request) tryOnce() (*result, bool) {
+// doWithPolicy 使用可插拔的 RetryPolicy 驱动请求，替代固定间隔的重试逻辑。
+func (r *request) doWithPolicy() Result {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var (
+		rt    *result
+		resp  *http.Response
+		err   error
+		start = time.Now()
+	)
+
+	for attempt := 0; ; attempt++ {
+		rt, resp, err = r.tryOnceWithPolicy(attempt)
+		if !r.retryPolicy.ShouldRetry(attempt, time.Since(start), resp, err) {
+			return rt
+		}
+
+		interval := r.retryPolicy.NextInterval(attempt, resp)
+
+		select {
+		case <-ctx.Done():
+			rt.err = ctx.Err()
+			return rt
+		case <-time.After(interval):
+		}
+	}
+}
+
+// tryOnceWithPolicy 与 tryOnce 类似，但返回原始响应以便 RetryPolicy 做决策；
+// 无论是连接错误还是 502/503/504 状态码，都只在幂等方法上把错误/响应交给 RetryPolicy，
+// 非幂等方法一律当作终态返回，避免重新提交已持久化的请求体。
+func (r *request) tryOnceWithPolicy(attempt int) (*result, *http.Response, error) {
 	rt := &result{}
 	u := r.wrapURL().String()
 	req, err := http.NewRequest(r.method, u, bytes.NewReader(r.body))
 	if err != nil {
 		rt.err = err
-		return rt, false
+		return rt, nil, err
+	}
+
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
+	ctx, endSpan := r.tracing.startAttempt(ctx, r.method, u, attempt)
+	var statusCode int
+	defer func() { endSpan(statusCode, rt.err) }()
+
 	if r.timeout > 0 {
-		if r.ctx == nil {
-			r.ctx = context.Background()
+		var cancelFn context.CancelFunc
+		ctx, cancelFn = context.WithTimeout(ctx, r.timeout)
+		defer cancelFn()
+	}
+
+	req = req.WithContext(ctx)
+
+	req.Header = r.header.Clone()
+	if len(req.Header) == 0 {
+		req.Header = make(http.Header)
+	}
+
+	codec := r.codecOrDefault()
+	req.Header.Del("Accept-Encoding")
+	req.Header.Set("Content-Type", codec.ContentType())
+	req.Header.Set("Accept", codec.ContentType())
+	req.Header.Set("Accept-Charset", "utf-8")
+
+	if err := r.applyAuth(req); err != nil {
+		rt.err = err
+		return rt, nil, err
+	}
+
+	client := r.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		rt.err = err
+		if !isIdempotent(r.method) {
+			// 非幂等方法上的连接错误不应交给 RetryPolicy 重试判断，否则可能重新提交已持久化的请求体。
+			return rt, nil, nil
 		}
+		return rt, nil, err
+	}
+	defer resp.Body.Close()
+	statusCode = resp.StatusCode
+
+	var body []byte
+	if resp.Body != nil {
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			rt.err = err
+			return rt, resp, err
+		}
+
+		body = data
+	}
+
+	rt.body = body
+	rt.statusCode = resp.StatusCode
+	rt.codec = codecForContentType(resp.Header.Get("Content-Type"), codec)
+
+	if !isIdempotent(r.method) || !retryableStatusCodes[resp.StatusCode] {
+		return rt, nil, nil
+	}
+
+	return rt, resp, nil
+}
 
+func (r *request) tryOnce(attempt int) (*result, bool) {
+	rt := &result{}
+	u := r.wrapURL().String()
+	req, err := http.NewRequest(r.method, u, bytes.NewReader(r.body))
+	if err != nil {
+		rt.err = err
+		return rt, false
+	}
+
+	if r.ctx == nil {
+		r.ctx = context.Background()
+	}
+
+	ctx, endSpan := r.tracing.startAttempt(r.ctx, r.method, u, attempt)
+	var statusCode int
+	defer func() { endSpan(statusCode, rt.err) }()
+
+	if r.timeout > 0 {
 		var cancelFn context.CancelFunc
-		r.ctx, cancelFn = context.WithTimeout(r.ctx, r.timeout)
+		ctx, cancelFn = context.WithTimeout(ctx, r.timeout)
 		defer cancelFn()
 	}
 
-	if r.ctx != nil {
-		req = req.WithContext(r.ctx)
-	}
+	req = req.WithContext(ctx)
 
 	req.Header = r.header.Clone()
 	if len(req.Header) == 0 {
 		req.Header = make(http.Header)
 	}
 
+	codec := r.codecOrDefault()
 	req.Header.Del("Accept-Encoding")
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", codec.ContentType())
+	req.Header.Set("Accept", codec.ContentType())
 	req.Header.Set("Accept-Charset", "utf-8")
 
-	req.SetBasicAuth(r.username, r.password)
+	if err := r.applyAuth(req); err != nil {
+		rt.err = err
+		return rt, false
+	}
 
 	client := r.client
 	if client == nil {
@@ -243,6 +402,8 @@ func (r *request) tryOnce() (*result, bool) {
 
 		return rt, true
 	}
+	defer resp.Body.Close()
+	statusCode = resp.StatusCode
 
 	var body []byte
 	if resp.Body != nil {
@@ -261,6 +422,7 @@ func (r *request) tryOnce() (*result, bool) {
 
 	rt.body = body
 	rt.statusCode = resp.StatusCode
+	rt.codec = codecForContentType(resp.Header.Get("Content-Type"), codec)
 
 	return rt, false
 }
@@ -1,8 +1,6 @@
 package rest
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 )
 
@@ -16,6 +14,7 @@ type (
 		body       []byte
 		err        error
 		statusCode int
+		codec      Codec
 	}
 )
 
@@ -37,8 +36,12 @@ func (r *result) Into(obj interface{}) error {
 			return fmt.Errorf("empty response body with status code: %d", r.statusCode)
 		}
 
-		err := json.NewDecoder(bytes.NewReader(r.body)).Decode(obj)
-		if err != nil {
+		codec := r.codec
+		if codec == nil {
+			codec = JSONCodec
+		}
+
+		if err := codec.Unmarshal(r.body, obj); err != nil {
 			return fmt.Errorf("decode response body error: %v", err)
 		}
 	}
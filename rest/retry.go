@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type (
+	// RetryPolicy 决定某次请求是否需要重试，以及下一次重试前需要等待的时长。
+	// 实现应当是无状态的：同一个 RetryPolicy 实例会被多个（可能并发的）请求复用，
+	// 所有随尝试次数变化的状态都必须由调用方（doWithPolicy）在参数中传入。
+	RetryPolicy interface {
+		// ShouldRetry 根据当前尝试次数（从 0 开始）、请求发起以来的耗时 elapsed、
+		// 响应和错误判断是否需要重试。
+		ShouldRetry(attempt int, elapsed time.Duration, resp *http.Response, err error) bool
+		// NextInterval 返回下一次重试前的等待时长。
+		NextInterval(attempt int, resp *http.Response) time.Duration
+	}
+
+	// ExponentialBackoffPolicy 是一个带抖动的指数退避重试策略。
+	ExponentialBackoffPolicy struct {
+		InitialInterval     time.Duration
+		Multiplier          float64
+		MaxInterval         time.Duration
+		MaxElapsedTime      time.Duration
+		RandomizationFactor float64
+	}
+)
+
+var retryableStatusCodes = map[int]bool{
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// NewExponentialBackoffPolicy 返回一个使用默认参数的指数退避策略。
+func NewExponentialBackoffPolicy() *ExponentialBackoffPolicy {
+	return &ExponentialBackoffPolicy{
+		InitialInterval:     500 * time.Millisecond,
+		Multiplier:          1.5,
+		MaxInterval:         30 * time.Second,
+		MaxElapsedTime:      2 * time.Minute,
+		RandomizationFactor: 0.5,
+	}
+}
+
+func (p *ExponentialBackoffPolicy) ShouldRetry(attempt int, elapsed time.Duration, resp *http.Response, err error) bool {
+	if p.MaxElapsedTime > 0 && elapsed > p.MaxElapsedTime {
+		return false
+	}
+
+	if err != nil {
+		return isConnectionReset(err)
+	}
+
+	if resp == nil {
+		return false
+	}
+
+	return retryableStatusCodes[resp.StatusCode]
+}
+
+func (p *ExponentialBackoffPolicy) NextInterval(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp); ok {
+			return d
+		}
+	}
+
+	interval := float64(p.InitialInterval)
+	for i := 0; i < attempt; i++ {
+		interval *= p.Multiplier
+	}
+
+	if max := float64(p.MaxInterval); p.MaxInterval > 0 && interval > max {
+		interval = max
+	}
+
+	delta := interval * p.RandomizationFactor
+	interval += delta*2*rand.Float64() - delta
+
+	if interval < 0 {
+		interval = 0
+	}
+
+	return time.Duration(interval)
+}
+
+// retryAfter 解析响应的 Retry-After 头，支持秒数和 HTTP 日期两种格式。
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	return idempotentMethods[method]
+}
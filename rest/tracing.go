@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestTracer 承载一次 Do() 调用期间可观测性所需的状态，默认零值下完全不产生开销。
+type requestTracer struct {
+	tracer           trace.Tracer
+	latencyHistogram metric.Float64Histogram
+	retryCounter     metric.Int64Counter
+}
+
+func newRequestTracer(tracer trace.Tracer, meter metric.Meter) requestTracer {
+	rt := requestTracer{tracer: tracer}
+	if meter != nil {
+		rt.latencyHistogram, _ = meter.Float64Histogram("rest_client_request_latency_seconds")
+		rt.retryCounter, _ = meter.Int64Counter("rest_client_retry_total")
+	}
+
+	return rt
+}
+
+// startAttempt 为一次请求尝试打开一个 span（如果配置了 tracer），并在结束时记录延迟/重试指标
+// （如果配置了 meter）。调用方须在结束时调用返回的 end，两者都未配置时开销为零。
+func (rt requestTracer) startAttempt(ctx context.Context, method, url string, attempt int) (context.Context, func(statusCode int, err error)) {
+	start := time.Now()
+
+	var span trace.Span
+	if rt.tracer != nil {
+		ctx, span = rt.tracer.Start(ctx, "rest.request.Do", trace.WithAttributes(
+			attribute.String("http.method", method),
+			attribute.String("http.url", url),
+			attribute.Int("retry.count", attempt),
+		))
+	}
+
+	return ctx, func(statusCode int, err error) {
+		if rt.latencyHistogram != nil {
+			rt.latencyHistogram.Record(ctx, time.Since(start).Seconds())
+		}
+
+		if attempt > 0 && rt.retryCounter != nil {
+			rt.retryCounter.Add(ctx, 1)
+		}
+
+		if span == nil {
+			return
+		}
+
+		if statusCode > 0 {
+			span.SetAttributes(attribute.Int("http.status_code", statusCode))
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}
+}
+
+// WithTracer 为 RESTClient 发出的每次请求尝试开启 OTel 链路追踪，默认不开启。
+func (rc *restClient) WithTracer(tracer trace.Tracer) RESTClient {
+	rc.tracer = tracer
+	return rc
+}
+
+// WithMeter 为 RESTClient 启用请求延迟直方图与重试计数器，默认不开启。
+func (rc *restClient) WithMeter(meter metric.Meter) RESTClient {
+	rc.meter = meter
+	return rc
+}